@@ -0,0 +1,84 @@
+// Copyright 2019-present Facebook
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entgql
+
+import "entgo.io/ent/schema"
+
+// Annotation is a builtin schema annotation for configuring the GraphQL
+// codegen behavior for fields, edges and types. Multiple annotations on the
+// same element are merged into one (e.g. entgql.GlobalID(), entgql.Type("ID")).
+type Annotation struct {
+	// Type overrides the default GraphQL type inferred from the Go field type.
+	Type string `json:"Type,omitempty"`
+	// GlobalIDField marks the field as holding an encoded global ID value
+	// (see GlobalID).
+	GlobalIDField bool `json:"GlobalIDField,omitempty"`
+	// GlobalIDRefType holds the name of the ent type a foreign global ID
+	// field is expected to reference (see GlobalIDRef).
+	GlobalIDRefType string `json:"GlobalIDRefType,omitempty"`
+}
+
+// Name implements the ent Annotation interface.
+func (Annotation) Name() string {
+	return "EntGQL"
+}
+
+// Merge implements the ent Annotation interface by merging a annotation into
+// the receiver. Non-zero fields on other take precedence.
+func (a Annotation) Merge(other schema.Annotation) schema.Annotation {
+	var ant Annotation
+	switch other := other.(type) {
+	case Annotation:
+		ant = other
+	case *Annotation:
+		if other != nil {
+			ant = *other
+		}
+	default:
+		return a
+	}
+	if ant.Type != "" {
+		a.Type = ant.Type
+	}
+	if ant.GlobalIDField {
+		a.GlobalIDField = true
+	}
+	if ant.GlobalIDRefType != "" {
+		a.GlobalIDRefType = ant.GlobalIDRefType
+	}
+	return a
+}
+
+// GlobalID returns an annotation for marking a field as a Relay global ID,
+// i.e. it will be encoded/decoded using the configured GlobalIDCodec instead
+// of being exposed as its underlying type.
+func GlobalID() Annotation {
+	return Annotation{GlobalIDField: true}
+}
+
+// Type overrides the default GraphQL type generated for a field or edge.
+func Type(name string) Annotation {
+	return Annotation{Type: name}
+}
+
+// GlobalIDRef annotates a global ID field that has no corresponding ent edge
+// with the name of the ent type it references (e.g. GlobalIDRef("Post") for
+// a "post_id" field). It only records the type name: the code generator does
+// not read it, so it neither validates decoded global IDs nor emits a
+// synthetic Relay field. DecodeForeignGlobalID and ForeignIDLoader are the
+// pieces a hand-written resolver uses to do both.
+func GlobalIDRef(typeName string) Annotation {
+	return Annotation{GlobalIDRefType: typeName}
+}
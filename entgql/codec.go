@@ -0,0 +1,131 @@
+// Copyright 2019-present Facebook
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entgql
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// GlobalIDCodec encodes/decodes the opaque string handed out as a Relay
+// global ID (and, by extension, as a pagination cursor built from one).
+// Projects that don't want their table names and raw primary keys visible to
+// anyone who base64-decodes an ID can supply their own via
+// WithGlobalIDCodec; the default is Base64Codec, which matches entgql's
+// historical (pre-codec) encoding.
+type GlobalIDCodec interface {
+	Encode(typ string, id driver.Value) (string, error)
+	Decode(raw string) (typ string, id driver.Value, err error)
+}
+
+// codecValue holds the codec used by GlobalID.String and FromGlobalID (read
+// through defaultCodec). It is process-wide because those are called from
+// generated code that has no other place to carry per-project configuration
+// through; SetGlobalIDCodec (which NewConfig calls) is the only way to change
+// it. It's stored in an atomic.Value rather than a
+// plain var because GlobalID.String/FromGlobalID are called concurrently
+// from resolver goroutines while SetGlobalIDCodec may run during a request
+// (e.g. a hot key rotation).
+//
+// HARD CONSTRAINT: there is exactly one codec per process, not one per
+// Config/schema. If two entgql-generated schemas run in the same process
+// with different WithGlobalIDCodec options, whichever NewConfig/
+// SetGlobalIDCodec call happens last wins for both — the other schema's IDs
+// silently start being encoded/decoded with the wrong codec. Do not run
+// multiple differently-configured schemas in one process; if that's needed,
+// give each its own process (or thread the codec through GlobalID/
+// FromGlobalID call sites explicitly instead of relying on this global).
+var codecValue atomic.Value
+
+func init() {
+	codecValue.Store(codecHolder{Base64Codec{}})
+}
+
+// codecHolder boxes a GlobalIDCodec so atomic.Value.Store always sees the
+// same concrete type, since GlobalIDCodec implementations aren't guaranteed
+// to be comparable/identical types across calls.
+type codecHolder struct{ GlobalIDCodec }
+
+func defaultCodec() GlobalIDCodec {
+	return codecValue.Load().(codecHolder).GlobalIDCodec
+}
+
+// SetGlobalIDCodec replaces the codec used by GlobalID.String and
+// FromGlobalID. Generated projects call this once at startup, typically via
+// a Config built with WithGlobalIDCodec; it is also safe to call while
+// requests are in flight, e.g. to rotate to a new key.
+func SetGlobalIDCodec(codec GlobalIDCodec) {
+	if codec == nil {
+		codec = Base64Codec{}
+	}
+	codecValue.Store(codecHolder{codec})
+}
+
+// Config configures process-wide entgql behavior that can't be expressed as
+// a schema annotation, such as the GlobalIDCodec used to mint and read
+// global IDs. Despite being constructed per schema, GlobalIDCodec ends up
+// installed as the single process-wide default (see defaultCodec) — build
+// at most one Config with a non-default GlobalIDCodec per process.
+type Config struct {
+	GlobalIDCodec GlobalIDCodec
+}
+
+// ConfigOption configures a Config.
+type ConfigOption func(*Config)
+
+// WithGlobalIDCodec sets the codec used to encode/decode global IDs (and,
+// transitively, pagination cursors built from them). Defaults to
+// Base64Codec.
+func WithGlobalIDCodec(codec GlobalIDCodec) ConfigOption {
+	return func(c *Config) { c.GlobalIDCodec = codec }
+}
+
+// NewConfig builds a Config from opts and installs its GlobalIDCodec as the
+// process-wide default.
+func NewConfig(opts ...ConfigOption) *Config {
+	cfg := &Config{GlobalIDCodec: Base64Codec{}}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	SetGlobalIDCodec(cfg.GlobalIDCodec)
+	return cfg
+}
+
+// Base64Codec is the historical GlobalIDCodec: it base64-encodes
+// "Type:ID" verbatim. It is predictable (the type name and raw primary key
+// are visible to anyone who base64-decodes a cursor) but requires no
+// configuration, which is why it remains the default.
+type Base64Codec struct{}
+
+// Encode implements GlobalIDCodec.
+func (Base64Codec) Encode(typ string, id driver.Value) (string, error) {
+	return base64.StdEncoding.EncodeToString([]byte(typ + ":" + fmt.Sprint(id))), nil
+}
+
+// Decode implements GlobalIDCodec.
+func (Base64Codec) Decode(raw string) (typ string, id driver.Value, err error) {
+	b, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		return "", nil, fmt.Errorf("entgql: invalid global id %q: %w", raw, err)
+	}
+	parts := strings.SplitN(string(b), ":", 2)
+	if len(parts) != 2 {
+		return "", nil, fmt.Errorf("entgql: invalid global id %q: expected \"Type:ID\"", raw)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2019-present Facebook
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entgql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGlobalIDCodecs(t *testing.T) {
+	aead, err := NewAEADCodec(1, map[byte][]byte{
+		1: []byte("0123456789abcdef0123456789abcdef"[:32]),
+		2: []byte("fedcba9876543210fedcba9876543210"[:32]),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codecs := map[string]GlobalIDCodec{
+		"base64": Base64Codec{},
+		"aead":   aead,
+	}
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			raw, err := codec.Encode("User", "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d")
+			if err != nil {
+				t.Fatalf("Encode() unexpected error: %v", err)
+			}
+			typ, id, err := codec.Decode(raw)
+			if err != nil {
+				t.Fatalf("Decode() unexpected error: %v", err)
+			}
+			if typ != "User" || id != "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d" {
+				t.Fatalf("Decode() = (%q, %v), want (User, 9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d)", typ, id)
+			}
+		})
+	}
+}
+
+func TestAEADCodecOpaque(t *testing.T) {
+	codec, err := NewAEADCodec(1, map[byte][]byte{1: []byte("0123456789abcdef0123456789abcdef"[:32])})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := codec.Encode("User", "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(raw, "User") || strings.Contains(raw, "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d") {
+		t.Fatalf("AEADCodec.Encode() leaked plaintext: %s", raw)
+	}
+}
+
+func TestAEADCodecKeyRotation(t *testing.T) {
+	oldCodec, err := NewAEADCodec(1, map[byte][]byte{1: []byte("0123456789abcdef0123456789abcdef"[:32])})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := oldCodec.Encode("User", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A codec that rotated to key 2 as active must still decode cursors
+	// minted under key 1, as long as key 1 stays registered.
+	rotated, err := NewAEADCodec(2, map[byte][]byte{
+		1: []byte("0123456789abcdef0123456789abcdef"[:32]),
+		2: []byte("fedcba9876543210fedcba9876543210"[:32]),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	typ, id, err := rotated.Decode(raw)
+	if err != nil {
+		t.Fatalf("Decode() unexpected error after rotation: %v", err)
+	}
+	if typ != "User" || id != "1" {
+		t.Fatalf("Decode() = (%q, %v), want (User, 1)", typ, id)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2019-present Facebook
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entgql
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Noder is implemented by every generated ent type that can be looked up
+// through the Relay node()/nodes() fields. It mirrors ent.Noder so that this
+// package does not need to import generated code.
+type Noder interface {
+	IsNode()
+}
+
+// NodesFetcher batch-resolves nodes by their global ID, mirroring the
+// generated (*ent.Client).Noders method used to serve nodes(ids:).
+type NodesFetcher interface {
+	Noders(ctx context.Context, ids []string) ([]Noder, error)
+}
+
+// DecodeForeignGlobalID decodes and validates a global ID supplied for a
+// field annotated with GlobalIDRef, e.g. Video.post_id declared as:
+//
+//	field.UUID("post_id", uuid.UUID{}).
+//		Annotations(entgql.GlobalID(), entgql.Type("ID"), entgql.GlobalIDRef("Post"))
+//
+// DecodeForeignGlobalID rejects the value if the encoded type does not match
+// expectType, e.g. a syntactically valid "User" global ID passed where a
+// "Post" one is expected.
+func DecodeForeignGlobalID(expectType, raw string) (GlobalID, error) {
+	typ, id, err := FromGlobalID(raw)
+	if err != nil {
+		return GlobalID{}, err
+	}
+	if typ != expectType {
+		return GlobalID{}, fmt.Errorf("entgql: global id %q references %s, not %s", raw, typ, expectType)
+	}
+	return GlobalID{Type: typ, ID: id}, nil
+}
+
+// DefaultForeignIDLoaderWait is how long ForeignIDLoader waits after the
+// first queued Load before dispatching, giving sibling resolver goroutines
+// (e.g. one per row in the same GraphQL selection) a chance to enqueue their
+// own lookups into the same batch.
+const DefaultForeignIDLoaderWait = 1 * time.Millisecond
+
+// ForeignIDLoader batches resolution of foreign global-ID fields within a
+// single GraphQL request/response cycle, so that N rows referencing the same
+// target type (e.g. N videos, each with a post_id) issue one nodes() query
+// instead of N. It is safe for concurrent use and is typically stashed on
+// the request context by a resolver middleware, one instance per Noder type.
+type ForeignIDLoader struct {
+	fetch NodesFetcher
+	wait  time.Duration
+
+	mu      sync.Mutex
+	pending map[string][]chan result
+	timer   *time.Timer
+}
+
+type result struct {
+	node Noder
+	err  error
+}
+
+// NewForeignIDLoader returns a loader that resolves foreign global IDs via
+// fetch, batching lookups queued within DefaultForeignIDLoaderWait of the
+// first one into a single NodesFetcher.Noders call.
+func NewForeignIDLoader(fetch NodesFetcher) *ForeignIDLoader {
+	return &ForeignIDLoader{fetch: fetch, wait: DefaultForeignIDLoaderWait, pending: map[string][]chan result{}}
+}
+
+// Load queues id for resolution and blocks until the batch it landed in has
+// been dispatched and resolved.
+func (l *ForeignIDLoader) Load(ctx context.Context, id string) (Noder, error) {
+	ch := make(chan result, 1)
+
+	l.mu.Lock()
+	l.pending[id] = append(l.pending[id], ch)
+	if l.timer == nil {
+		l.timer = time.AfterFunc(l.wait, func() { l.dispatch(ctx) })
+	}
+	l.mu.Unlock()
+
+	res := <-ch
+	return res.node, res.err
+}
+
+func (l *ForeignIDLoader) dispatch(ctx context.Context) {
+	l.mu.Lock()
+	pending := l.pending
+	l.pending = map[string][]chan result{}
+	l.timer = nil
+	l.mu.Unlock()
+
+	ids := make([]string, 0, len(pending))
+	for id := range pending {
+		ids = append(ids, id)
+	}
+
+	nodes, err := l.fetch.Noders(ctx, ids)
+	if err != nil {
+		// A single dangling/invalid id in the batch must not fail sibling
+		// lookups that would otherwise have resolved fine on their own, so
+		// fall back to resolving each id in this batch on its own.
+		for id, chans := range pending {
+			node, err := l.loadOne(ctx, id)
+			for _, ch := range chans {
+				ch <- result{node: node, err: err}
+			}
+		}
+		return
+	}
+
+	byID := make(map[string]Noder, len(nodes))
+	for i, n := range nodes {
+		byID[ids[i]] = n
+	}
+	for id, chans := range pending {
+		n, ok := byID[id]
+		var err error
+		if !ok {
+			err = fmt.Errorf("entgql: no node found for id %q", id)
+		}
+		for _, ch := range chans {
+			ch <- result{node: n, err: err}
+		}
+	}
+}
+
+func (l *ForeignIDLoader) loadOne(ctx context.Context, id string) (Noder, error) {
+	nodes, err := l.fetch.Noders(ctx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("entgql: no node found for id %q", id)
+	}
+	return nodes[0], nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2019-present Facebook
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entgql
+
+import "testing"
+
+func TestDecodeForeignGlobalID(t *testing.T) {
+	raw := GlobalID{Type: "Post", ID: "1"}.String()
+
+	got, err := DecodeForeignGlobalID("Post", raw)
+	if err != nil {
+		t.Fatalf("DecodeForeignGlobalID() unexpected error: %v", err)
+	}
+	if got.Type != "Post" || got.ID != "1" {
+		t.Fatalf("DecodeForeignGlobalID() = %+v, want {Post 1}", got)
+	}
+
+	if _, err := DecodeForeignGlobalID("User", raw); err == nil {
+		t.Fatal("DecodeForeignGlobalID() expected error for mismatched type, got nil")
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2019-present Facebook
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entgql
+
+import "fmt"
+
+// GlobalID is the decoded representation of a Relay global ID: the name of
+// the ent type it belongs to and its underlying primary key, formatted as a
+// string.
+type GlobalID struct {
+	Type string
+	ID   string
+}
+
+// String encodes the global ID using the process's configured GlobalIDCodec
+// (Base64Codec by default, see SetGlobalIDCodec/WithGlobalIDCodec). Generated
+// types embed a GlobalID() method returning a GlobalID built from the type's
+// name and its own primary key, so callers usually reach this through e.g.
+// u.GlobalID().String().
+func (g GlobalID) String() string {
+	s, err := defaultCodec().Encode(g.Type, g.ID)
+	if err != nil {
+		// Base64Codec never errors, and a project that configures a codec
+		// that can fail (e.g. because a key isn't loaded yet) should not be
+		// silently handed an empty cursor.
+		panic(fmt.Errorf("entgql: encode global id %+v: %w", g, err))
+	}
+	return s
+}
+
+// FromGlobalID decodes raw into its type name and primary key using the
+// process's configured GlobalIDCodec. Generated Noder/Noders implementations
+// call this to resolve a node(id:) or nodes(ids:) query against the right
+// table, and pagination cursors built from global IDs decode the same way.
+func FromGlobalID(raw string) (typ, id string, err error) {
+	typ, v, err := defaultCodec().Decode(raw)
+	if err != nil {
+		return "", "", err
+	}
+	id, ok := v.(string)
+	if !ok {
+		return "", "", fmt.Errorf("entgql: invalid global id %q: decoded id %v is not a string", raw, v)
+	}
+	return typ, id, nil
+}
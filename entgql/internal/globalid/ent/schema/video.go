@@ -31,6 +31,11 @@ func (Video) Fields() []ent.Field {
 	return []ent.Field{
 		field.UUID("id", uuid.UUID{}).Default(uuid.New).Annotations(entgql.GlobalID()),
 		field.String("name"),
-		field.UUID("post_id", uuid.UUID{}).Annotations(entgql.GlobalID(), entgql.Type("ID")), // no edge definition
+		// post_id has no edge to Post; entgql.GlobalIDRef records that its
+		// value refers to a Post. The code generator does not read it: the
+		// "post" field resolved through the Noder interface is hand-written
+		// in gql_foreignid.go of the globalid package.
+		field.UUID("post_id", uuid.UUID{}).
+			Annotations(entgql.GlobalID(), entgql.Type("ID"), entgql.GlobalIDRef("Post")),
 	}
 }
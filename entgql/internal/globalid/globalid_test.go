@@ -27,15 +27,18 @@ import (
 	"entgo.io/contrib/entgql/internal/globalid/ent/migrate"
 	"entgo.io/ent/dialect"
 	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
 type globalidTestSuite struct {
 	suite.Suite
 	*client.Client
-	ent *ent.Client
+	ent   *ent.Client
+	codec entgql.GlobalIDCodec
 }
 
 const (
@@ -60,6 +63,8 @@ const (
 )
 
 func (s *globalidTestSuite) SetupTest() {
+	entgql.SetGlobalIDCodec(s.codec)
+
 	s.ent = enttest.Open(s.T(), dialect.SQLite,
 		fmt.Sprintf("file:%s-%d?mode=memory&cache=shared&_fk=1",
 			s.T().Name(), time.Now().UnixNano(),
@@ -69,11 +74,29 @@ func (s *globalidTestSuite) SetupTest() {
 
 	srv := handler.NewDefaultServer(gen.NewSchema(s.ent))
 	srv.Use(entgql.Transactioner{TxOpener: s.ent})
+	srv.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		return next(gen.WithVideoPostLoader(ctx, s.ent))
+	})
 	s.Client = client.New(srv)
 }
 
+// TestGlobalID runs the whole suite once per GlobalIDCodec, so that every
+// scenario (pagination, node/nodes, the foreign global ID field) is proven
+// to work the same way whether IDs are plain base64 or AEAD-sealed.
 func TestGlobalID(t *testing.T) {
-	suite.Run(t, &globalidTestSuite{})
+	aead, err := entgql.NewAEADCodec(1, map[byte][]byte{
+		1: []byte("0123456789abcdef0123456789abcdef"[:32]),
+	})
+	require.NoError(t, err)
+
+	for name, codec := range map[string]entgql.GlobalIDCodec{
+		"base64": entgql.Base64Codec{},
+		"aead":   aead,
+	} {
+		t.Run(name, func(t *testing.T) {
+			suite.Run(t, &globalidTestSuite{codec: codec})
+		})
+	}
 }
 
 type response struct {
@@ -214,3 +237,141 @@ func (s *globalidTestSuite) TestNodes() {
 	s.Require().Equal("U1", rsp.Users[0].Name)
 	s.Require().Equal("U2", rsp.Users[1].Name)
 }
+
+func (s *globalidTestSuite) TestForeignGlobalID() {
+	ctx := context.Background()
+	p := s.ent.Post.Create().SetText("hello").SaveX(ctx)
+	v := s.ent.Video.Create().SetName("V1").SetPostID(p.ID).SaveX(ctx)
+
+	const (
+		query = `query($id: ID!) {
+			video: node(id: $id) {
+				... on Video {
+					id
+					name
+					postID
+					post {
+						id
+						text
+					}
+				}
+			}
+		}`
+	)
+	var rsp struct {
+		Video struct {
+			ID     string
+			Name   string
+			PostID string
+			Post   struct {
+				ID   string
+				Text string
+			}
+		}
+	}
+	err := s.Post(query, &rsp, client.Var("id", v.GlobalID().String()))
+	s.Require().NoError(err)
+	s.Require().Equal(p.GlobalID().String(), rsp.Video.PostID)
+	s.Require().Equal(p.GlobalID().String(), rsp.Video.Post.ID)
+	s.Require().Equal("hello", rsp.Video.Post.Text)
+}
+
+// TestForeignGlobalIDDanglingReference covers a post_id that doesn't match
+// any Post row. Because post_id is a raw UUID column with no type tag of its
+// own, this is the only kind of bad reference that can exist in the
+// database; it is a dangling reference, not a type mismatch, since nothing
+// here is ever decoded as a "User" global ID. The actual type-mismatch check
+// performed by DecodeForeignGlobalID (e.g. decoding a "User" global ID where
+// a "Post" one is expected) is covered directly in foreign_id_test.go.
+func (s *globalidTestSuite) TestForeignGlobalIDDanglingReference() {
+	ctx := context.Background()
+	u := s.ent.User.Create().SetName("U1").SaveX(ctx)
+	// No Post with this primary key exists; post_id only ever holds a raw
+	// UUID, so the way to simulate an invalid reference is to point it at
+	// another table's row rather than at a (nonexistent) encoded global ID.
+	v := s.ent.Video.Create().SetName("V1").SetPostID(u.ID).SaveX(ctx)
+
+	const (
+		query = `query($id: ID!) {
+			video: node(id: $id) {
+				... on Video {
+					post {
+						id
+					}
+				}
+			}
+		}`
+	)
+	var rsp struct {
+		Video struct {
+			Post struct{ ID string }
+		}
+	}
+	err := s.Post(query, &rsp, client.Var("id", v.GlobalID().String()))
+	s.Require().Error(err)
+}
+
+// TestForeignGlobalIDBatches resolves "post" on two videos in a single
+// nodes(ids:) query — one with a valid post_id, one dangling — so that both
+// ForeignIDLoader's debounced batching (one Noders call for the two
+// in-flight Load calls, not two) and its per-id error-isolation fallback in
+// dispatch (the dangling id's error must not take down its batch-mate) are
+// actually exercised, not just the single-video path.
+func (s *globalidTestSuite) TestForeignGlobalIDBatches() {
+	ctx := context.Background()
+	p := s.ent.Post.Create().SetText("hello").SaveX(ctx)
+	v1 := s.ent.Video.Create().SetName("V1").SetPostID(p.ID).SaveX(ctx)
+	other := s.ent.User.Create().SetName("not-a-post").SaveX(ctx)
+	v2 := s.ent.Video.Create().SetName("V2").SetPostID(other.ID).SaveX(ctx)
+
+	const (
+		query = `query($ids: [ID!]!) {
+			videos: nodes(ids: $ids) {
+				... on Video {
+					name
+					post {
+						id
+						text
+					}
+				}
+			}
+		}`
+	)
+	var rsp struct {
+		Videos []struct {
+			Name string
+			Post *struct {
+				ID   string
+				Text string
+			}
+		}
+	}
+	err := s.Post(query, &rsp, client.Var("ids", []string{
+		v1.GlobalID().String(),
+		v2.GlobalID().String(),
+	}))
+	s.Require().Error(err) // v2's dangling post_id surfaces as a field error
+	s.Require().Len(rsp.Videos, 2)
+	s.Require().Equal("V1", rsp.Videos[0].Name)
+	s.Require().NotNil(rsp.Videos[0].Post)
+	s.Require().Equal("hello", rsp.Videos[0].Post.Text)
+	s.Require().Equal("V2", rsp.Videos[1].Name)
+	s.Require().Nil(rsp.Videos[1].Post)
+}
+
+func (s *globalidTestSuite) TestGlobalIDRoundTrips() {
+	ctx := context.Background()
+	u := s.ent.User.Create().SetName("U1").SaveX(ctx)
+	raw := u.GlobalID().String()
+
+	typ, id, err := entgql.FromGlobalID(raw)
+	s.Require().NoError(err)
+	s.Require().Equal("User", typ)
+	s.Require().Equal(u.ID.String(), id)
+
+	if _, ok := s.codec.(entgql.Base64Codec); ok {
+		return // the default codec is intentionally not opaque
+	}
+	s.Require().NotContains(raw, "User")
+	s.Require().NotContains(raw, u.ID.String())
+}